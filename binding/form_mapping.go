@@ -0,0 +1,810 @@
+// Copyright 2014 Manu Martinez-Almeida. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package binding
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var errUnknownType = errors.New("unknown type")
+
+// Unmarshaler is implemented by types that want to decode a single form or
+// URI value into themselves, e.g. a custom type used with `form:"id"`.
+type Unmarshaler interface {
+	UnmarshalParam(param string) error
+}
+
+// FormUnmarshaler is implemented by types that need to see every value
+// submitted under a form/query key instead of just the first one, e.g. a
+// repeated query parameter such as "?tag=a&tag=b". When a field (or a
+// pointer to it) implements both FormUnmarshaler and Unmarshaler,
+// FormUnmarshaler is preferred.
+type FormUnmarshaler interface {
+	UnmarshalForm(values []string) error
+}
+
+func mapURI(ptr any, m map[string][]string) error {
+	return mapFormByTag(ptr, m, "uri")
+}
+
+func mapForm(ptr any, form map[string][]string) error {
+	return mapFormByTag(ptr, form, "form")
+}
+
+// MapFormWithTag maps form data into ptr using the given struct tag name
+// instead of the default "form" tag.
+func MapFormWithTag(ptr any, form map[string][]string, tag string) error {
+	return mapFormByTag(ptr, form, tag)
+}
+
+// CustomTypeFunc converts every value submitted for a field into a value of
+// the type it was registered for, so types gin doesn't otherwise know how to
+// bind (uuid.UUID, decimal.Decimal, net.IP, ...) can be used without a
+// wrapper type.
+type CustomTypeFunc func(values []string) (any, error)
+
+// customTypeRegistry is safe for concurrent registration (typically from
+// package init) and concurrent lookup (from request handling).
+type customTypeRegistry struct {
+	mu    sync.RWMutex
+	funcs map[reflect.Type]CustomTypeFunc
+}
+
+var globalCustomTypes = &customTypeRegistry{funcs: map[reflect.Type]CustomTypeFunc{}}
+
+// RegisterCustomTypeFunc registers fn as the converter used for every field
+// whose type matches one of types (given as zero values, e.g.
+// RegisterCustomTypeFunc(fn, uuid.UUID{}, net.IP{})). It takes priority over
+// gin's built-in type handling, including `time_format`. Safe to call from
+// an init function.
+func RegisterCustomTypeFunc(fn CustomTypeFunc, types ...any) {
+	globalCustomTypes.register(fn, types...)
+}
+
+func (r *customTypeRegistry) register(fn CustomTypeFunc, types ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range types {
+		r.funcs[reflect.TypeOf(t)] = fn
+	}
+}
+
+func (r *customTypeRegistry) lookup(t reflect.Type) (CustomTypeFunc, bool) {
+	if r == nil {
+		return nil, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	fn, ok := r.funcs[t]
+	return fn, ok
+}
+
+// FormOptions configures a single MapFormWithOptions call.
+type FormOptions struct {
+	// CustomTypes is consulted before the package-level registry
+	// (RegisterCustomTypeFunc), and only for this call.
+	CustomTypes map[reflect.Type]CustomTypeFunc
+}
+
+// MapFormWithOptions is like mapForm but accepts a per-call converter
+// registry, for callers that need a converter scoped to one request instead
+// of registered globally.
+func MapFormWithOptions(ptr any, form map[string][]string, opts FormOptions) error {
+	tree := buildFormTree(form)
+	registry := &customTypeRegistry{funcs: opts.CustomTypes}
+	return mappingByPtr(ptr, formTreeSource{tree: tree, tag: "form", registry: registry}, "form")
+}
+
+var emptyField = reflect.StructField{}
+
+func mapFormByTag(ptr any, form map[string][]string, tag string) error {
+	tree := buildFormTree(form)
+	return mappingByPtr(ptr, formTreeSource{tree: tree, tag: tag}, tag)
+}
+
+// setter tries to set a value by walking the fields of a struct.
+type setter interface {
+	TrySet(value reflect.Value, field reflect.StructField, key string, opt setOptions) (isSetted bool, err error)
+}
+
+// formKeyNode is one step of a parsed form key path, e.g. "items[0].key"
+// becomes root -> "items" -> index 0 -> "key". A node can carry both leaf
+// values (it was submitted directly, "items") and further structure
+// (it was also indexed/dotted into, "items[0]...").
+type formKeyNode struct {
+	values   []string
+	children map[string]*formKeyNode
+	indices  map[int]*formKeyNode
+}
+
+// Caps on index size and total tree nodes so a key like "items[999999999]"
+// can't force a huge slice allocation or an unbounded number of tree nodes.
+const (
+	maxFormKeyIndex = 100000
+	maxFormKeyNodes = 10000
+
+	// maxFormKeySliceSparsity bounds how far maxIdx may run past the number
+	// of indices actually submitted for a slice field, so a single key like
+	// "items[99999]" (well under maxFormKeyIndex) can't still force a
+	// 100,000-element allocation on its own.
+	maxFormKeySliceSparsity = 64
+)
+
+func newFormKeyNode() *formKeyNode {
+	return &formKeyNode{children: map[string]*formKeyNode{}, indices: map[int]*formKeyNode{}}
+}
+
+// formKeySegment is one step of a parsed key. isIndex means the bracket
+// contents parsed as a non-negative integer ("m[123]"), but that's only ever
+// a guess at intent: "123" is equally a valid map[string]V key. Such a
+// segment carries both name and index, and buildFormTree files the node
+// under both cur.children and cur.indices so setNode can pick the right one
+// once it knows whether the destination is actually a slice or a map.
+type formKeySegment struct {
+	name    string
+	index   int
+	isIndex bool
+}
+
+// parseFormKey splits a key such as "items[0].key", "user.name" or "m[foo]"
+// into a path of field-name and bracket-index segments. A plain key like
+// "F" comes back as a single field segment, so unbracketed keys are
+// unaffected.
+func parseFormKey(key string) []formKeySegment {
+	var segs []formKeySegment
+	for len(key) > 0 {
+		switch key[0] {
+		case '.':
+			key = key[1:]
+			continue
+		case '[':
+			end := strings.IndexByte(key, ']')
+			if end < 0 {
+				return nil
+			}
+			inner := key[1:end]
+			key = key[end+1:]
+			if idx, err := strconv.Atoi(inner); err == nil && idx >= 0 {
+				segs = append(segs, formKeySegment{name: inner, index: idx, isIndex: true})
+			} else {
+				segs = append(segs, formKeySegment{name: inner})
+			}
+			continue
+		}
+
+		end := len(key)
+		for i, c := range key {
+			if c == '.' || c == '[' {
+				end = i
+				break
+			}
+		}
+		segs = append(segs, formKeySegment{name: key[:end]})
+		key = key[end:]
+	}
+	return segs
+}
+
+// buildFormTree groups a flat form map by path so that mapping can walk
+// bracket-indexed and dotted keys the same way it walks plain struct tags.
+func buildFormTree(form map[string][]string) *formKeyNode {
+	root := newFormKeyNode()
+	nodeCount := 1
+
+	for key, vs := range form {
+		segs := parseFormKey(key)
+		if len(segs) == 0 {
+			continue
+		}
+
+		cur := root
+		for _, seg := range segs {
+			if cur == nil {
+				break
+			}
+			if seg.isIndex {
+				if seg.index > maxFormKeyIndex {
+					cur = nil
+					break
+				}
+				// Ambiguous segment: file the same node under both maps
+				// (keyed by its numeric text under children, e.g. a
+				// map[string]V keyed by "123") so whichever one setNode
+				// ends up consulting finds it.
+				child, ok := cur.children[seg.name]
+				if !ok {
+					if nodeCount >= maxFormKeyNodes {
+						cur = nil
+						break
+					}
+					child = newFormKeyNode()
+					cur.children[seg.name] = child
+					nodeCount++
+				}
+				cur.indices[seg.index] = child
+				cur = child
+			} else {
+				child, ok := cur.children[seg.name]
+				if !ok {
+					if nodeCount >= maxFormKeyNodes {
+						cur = nil
+						break
+					}
+					child = newFormKeyNode()
+					cur.children[seg.name] = child
+					nodeCount++
+				}
+				cur = child
+			}
+		}
+		if cur != nil {
+			cur.values = vs
+		}
+	}
+
+	return root
+}
+
+// formTreeSource drives mapping from a formKeyNode tree instead of a flat
+// map, so a struct/slice/map field whose key was indexed or dotted
+// recurses into the matching subtree instead of being treated as one
+// scalar value.
+type formTreeSource struct {
+	tree     *formKeyNode
+	tag      string
+	registry *customTypeRegistry // per-call override consulted ahead of the global registry
+}
+
+var _ setter = formTreeSource{}
+
+func (s formTreeSource) TrySet(value reflect.Value, field reflect.StructField, tagValue string, opt setOptions) (isSetted bool, err error) {
+	var node *formKeyNode
+	if s.tree != nil {
+		node = s.tree.children[tagValue]
+	}
+	return setNode(value, field, node, opt, s.tag, s.registry)
+}
+
+// setNode applies node to value: it recurses into structs, slices and maps
+// that were populated through indexed/dotted keys, and otherwise falls back
+// to treating node's own values like a plain form value (so `UnmarshalParam`,
+// `UnmarshalForm`, `delim`, `default`, and the scalar/slice/array handling
+// below all keep working unchanged at every level). A struct reached this
+// way is always through a named field's own subtree (an anonymous/embedded
+// field is flattened directly by mapping's own recursion instead, never
+// through here), so it gets its own fresh shadowState rather than the
+// caller's: two unrelated structs reached via different dotted paths must
+// not merge allowshadow values just because they reuse the same tag name.
+func setNode(value reflect.Value, field reflect.StructField, node *formKeyNode, opt setOptions, tag string, registry *customTypeRegistry) (bool, error) {
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+		return setNode(value.Elem(), field, node, opt, tag, registry)
+	}
+
+	if node != nil {
+		switch {
+		case value.Kind() == reflect.Struct && len(node.children) > 0:
+			inner := formTreeSource{tree: node, tag: tag, registry: registry}
+			shadow := &shadowState{seen: map[string]reflect.Value{}}
+			return mapping(value, emptyField, shadowAwareSetter{inner: inner, shadow: shadow}, tag)
+		case value.Kind() == reflect.Map && len(node.children) > 0:
+			return setMapByNode(value, node, tag, registry)
+		case value.Kind() == reflect.Slice && len(node.indices) > 0:
+			return setSliceByNode(value, node, field, tag, registry)
+		}
+	}
+
+	vs := node.leafValues(opt)
+	if vs == nil {
+		return false, nil
+	}
+	return setByValues(vs, value, field, opt, registry)
+}
+
+// leafValues returns the values to bind for a node, falling back to the
+// field's default. A nil node (key never submitted) behaves like a missing
+// flat-map key.
+func (n *formKeyNode) leafValues(opt setOptions) []string {
+	if n != nil && len(n.values) > 0 {
+		return n.values
+	}
+	if opt.isDefaultExists {
+		return []string{opt.defaultValue}
+	}
+	return nil
+}
+
+func setSliceByNode(value reflect.Value, node *formKeyNode, field reflect.StructField, tag string, registry *customTypeRegistry) (bool, error) {
+	maxIdx := -1
+	for i := range node.indices {
+		if i > maxIdx {
+			maxIdx = i
+		}
+	}
+	if maxIdx < 0 {
+		return false, nil
+	}
+	// A handful of submitted indices shouldn't be able to force an
+	// allocation orders of magnitude larger than what was actually sent.
+	if maxIdx+1 > len(node.indices)*maxFormKeySliceSparsity {
+		return false, nil
+	}
+
+	slice := reflect.MakeSlice(value.Type(), maxIdx+1, maxIdx+1)
+	for i := 0; i <= maxIdx; i++ {
+		if _, err := setNode(slice.Index(i), field, node.indices[i], setOptions{}, tag, registry); err != nil {
+			return false, err
+		}
+	}
+	value.Set(slice)
+	return true, nil
+}
+
+func setMapByNode(value reflect.Value, node *formKeyNode, tag string, registry *customTypeRegistry) (bool, error) {
+	if value.IsNil() {
+		value.Set(reflect.MakeMap(value.Type()))
+	}
+
+	keyType := value.Type().Key()
+	elemType := value.Type().Elem()
+	for k, child := range node.children {
+		key := reflect.New(keyType).Elem()
+		if err := setWithProperType(k, key, emptyField); err != nil {
+			return false, err
+		}
+		elem := reflect.New(elemType).Elem()
+		if _, err := setNode(elem, emptyField, child, setOptions{}, tag, registry); err != nil {
+			return false, err
+		}
+		value.SetMapIndex(key, elem)
+	}
+	return true, nil
+}
+
+func mappingByPtr(ptr any, src formTreeSource, tag string) error {
+	root := shadowAwareSetter{inner: src, shadow: &shadowState{seen: map[string]reflect.Value{}}}
+	_, err := mapping(reflect.ValueOf(ptr), emptyField, root, tag)
+	return err
+}
+
+// shadowState tracks, for one struct level (the top-level mappingByPtr call,
+// or one subtree entered through a dotted/bracketed key), the merged value
+// seen so far for each `allowshadow` key, so later occurrences (e.g. an
+// embedded struct's field sharing a form key with the outer struct) extend
+// rather than replace what earlier occurrences already set. It's scoped per
+// struct level rather than shared across the whole call so two unrelated
+// structs reached via different paths don't merge just because they reuse
+// the same tag name.
+type shadowState struct {
+	seen map[string]reflect.Value
+}
+
+// shadowAwareSetter wraps another setter and, for slice fields tagged
+// `allowshadow`, appends each occurrence's own value onto whatever was
+// already accumulated for that key instead of letting it overwrite in
+// place. Traversal order follows normal struct field order, so a field
+// declared before an embedded struct runs, and is seen, first.
+type shadowAwareSetter struct {
+	inner  setter
+	shadow *shadowState
+}
+
+var _ setter = shadowAwareSetter{}
+
+func (s shadowAwareSetter) TrySet(value reflect.Value, field reflect.StructField, tagValue string, opt setOptions) (isSetted bool, err error) {
+	isSetted, err = s.inner.TrySet(value, field, tagValue, opt)
+	if err != nil || !isSetted || !opt.allowShadow || value.Kind() != reflect.Slice {
+		return isSetted, err
+	}
+
+	prior, ok := s.shadow.seen[tagValue]
+	if !ok {
+		prior = reflect.MakeSlice(value.Type(), 0, 0)
+	}
+	merged := reflect.AppendSlice(prior, value)
+	value.Set(merged)
+	s.shadow.seen[tagValue] = merged
+	return true, nil
+}
+
+func mapping(value reflect.Value, field reflect.StructField, setter setter, tag string) (bool, error) {
+	if field.Tag.Get(tag) == "-" { // just ignoring this field
+		return false, nil
+	}
+
+	vKind := value.Kind()
+
+	if vKind == reflect.Ptr {
+		var isNew bool
+		vPtr := value
+		if value.IsNil() {
+			isNew = true
+			vPtr = reflect.New(value.Type().Elem())
+		}
+		isSetted, err := mapping(vPtr.Elem(), field, setter, tag)
+		if err != nil {
+			return false, err
+		}
+		if isNew && isSetted {
+			value.Set(vPtr)
+		}
+		return isSetted, nil
+	}
+
+	if vKind != reflect.Struct || !field.Anonymous {
+		ok, err := tryToSetValue(value, field, setter, tag)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+
+	if vKind == reflect.Struct {
+		tValue := value.Type()
+
+		var isSetted bool
+		for i := 0; i < value.NumField(); i++ {
+			sf := tValue.Field(i)
+			if sf.PkgPath != "" && !sf.Anonymous { // unexported
+				continue
+			}
+			ok, err := mapping(value.Field(i), tValue.Field(i), setter, tag)
+			if err != nil {
+				return false, err
+			}
+			isSetted = isSetted || ok
+		}
+		return isSetted, nil
+	}
+	return false, nil
+}
+
+type setOptions struct {
+	isDefaultExists bool
+	defaultValue    string
+	delim           string
+	allowShadow     bool
+}
+
+func tryToSetValue(value reflect.Value, field reflect.StructField, setter setter, tag string) (bool, error) {
+	tagValue := field.Tag.Get(tag)
+	tagValue, opts := head(tagValue, ",")
+
+	if tagValue == "" { // default value is FieldName
+		tagValue = field.Name
+	}
+	if tagValue == "" { // when field is the "emptyField" variable
+		return false, nil
+	}
+
+	var opt string
+	var setOpt setOptions
+	for len(opts) > 0 {
+		opt, opts = head(opts, ",")
+
+		switch k, v := head(opt, "="); k {
+		case "default":
+			setOpt.isDefaultExists = true
+			setOpt.defaultValue = v
+		case "delim":
+			setOpt.delim = unquoteDelim(v)
+		case "allowshadow":
+			setOpt.allowShadow = true
+		}
+	}
+
+	// a dedicated `delim` tag is also accepted (and takes precedence), since
+	// a comma delimiter can't be expressed as a `form:"...,delim=,"` suboption.
+	if d := field.Tag.Get("delim"); d != "" {
+		setOpt.delim = unquoteDelim(d)
+	}
+
+	return setter.TrySet(value, field, tagValue, setOpt)
+}
+
+func unquoteDelim(raw string) string {
+	if unquoted, err := strconv.Unquote(raw); err == nil {
+		return unquoted
+	}
+	return raw
+}
+
+// setByValues applies already-resolved form values to value, regardless of
+// whether they came from a flat map or a node in the structured key tree.
+// registry is the per-call converter registry in effect (nil outside
+// MapFormWithOptions); it is consulted ahead of the global registry, which in
+// turn is consulted ahead of the FormUnmarshaler/Unmarshaler hooks.
+func setByValues(vs []string, value reflect.Value, field reflect.StructField, opt setOptions, registry *customTypeRegistry) (isSetted bool, err error) {
+	if isSetted, err := trySetRegistered(vs, value, registry); isSetted {
+		return true, err
+	}
+	if isSetted, err := trySetCustom(vs, value); isSetted {
+		return true, err
+	}
+
+	switch value.Kind() {
+	case reflect.Slice:
+		return true, setSlice(splitByDelim(vs, opt.delim), value, field)
+	case reflect.Array:
+		vs = splitByDelim(vs, opt.delim)
+		if len(vs) != value.Len() {
+			return false, fmt.Errorf("%q is not valid value for %s", vs, value.Type().String())
+		}
+		return true, setArray(vs, value, field)
+	case reflect.Interface:
+		// an `any` field keeps a single value as a string, but holds on to
+		// every value (as []string) when the key was repeated.
+		if len(vs) == 1 {
+			value.Set(reflect.ValueOf(vs[0]))
+		} else {
+			value.Set(reflect.ValueOf(vs))
+		}
+		return true, nil
+	default:
+		var val string
+		if len(vs) > 0 {
+			val = vs[0]
+		}
+		return true, setWithProperType(val, value, field)
+	}
+}
+
+// trySetRegistered consults registry (if non-nil) and then the global
+// registry for a converter matching value's type, preferring a per-call
+// registration over a package-level one so callers can override built-in
+// or globally-registered behavior (e.g. time_format) for a single request.
+func trySetRegistered(vs []string, value reflect.Value, registry *customTypeRegistry) (isSetted bool, err error) {
+	if !value.CanSet() {
+		return false, nil
+	}
+
+	t := value.Type()
+	fn, ok := registry.lookup(t)
+	if !ok {
+		fn, ok = globalCustomTypes.lookup(t)
+	}
+	if !ok {
+		return false, nil
+	}
+
+	result, err := fn(vs)
+	if err != nil {
+		return true, err
+	}
+	value.Set(reflect.ValueOf(result))
+	return true, nil
+}
+
+// trySetCustom prefers FormUnmarshaler (which sees every submitted value)
+// over the single-value Unmarshaler hook, so a field only needs to
+// implement the one that matches how much of the form data it cares about.
+func trySetCustom(vs []string, value reflect.Value) (isSetted bool, err error) {
+	if !value.CanAddr() {
+		return false, nil
+	}
+
+	switch v := value.Addr().Interface().(type) {
+	case FormUnmarshaler:
+		return true, v.UnmarshalForm(vs)
+	case Unmarshaler:
+		var val string
+		if len(vs) > 0 {
+			val = vs[0]
+		}
+		return true, v.UnmarshalParam(val)
+	}
+	return false, nil
+}
+
+func setWithProperType(val string, value reflect.Value, field reflect.StructField) error {
+	switch value.Kind() {
+	case reflect.Int:
+		return setIntField(val, 0, value)
+	case reflect.Int8:
+		return setIntField(val, 8, value)
+	case reflect.Int16:
+		return setIntField(val, 16, value)
+	case reflect.Int32:
+		return setIntField(val, 32, value)
+	case reflect.Int64:
+		switch value.Interface().(type) {
+		case time.Duration:
+			return setTimeDuration(val, value)
+		}
+		return setIntField(val, 64, value)
+	case reflect.Uint:
+		return setUintField(val, 0, value)
+	case reflect.Uint8:
+		return setUintField(val, 8, value)
+	case reflect.Uint16:
+		return setUintField(val, 16, value)
+	case reflect.Uint32:
+		return setUintField(val, 32, value)
+	case reflect.Uint64:
+		return setUintField(val, 64, value)
+	case reflect.Bool:
+		return setBoolField(val, value)
+	case reflect.Float32:
+		return setFloatField(val, 32, value)
+	case reflect.Float64:
+		return setFloatField(val, 64, value)
+	case reflect.String:
+		value.SetString(val)
+	case reflect.Interface:
+		value.Set(reflect.ValueOf(val))
+	case reflect.Ptr:
+		if !value.Elem().IsValid() {
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+		return setWithProperType(val, value.Elem(), field)
+	case reflect.Struct:
+		switch value.Interface().(type) {
+		case time.Time:
+			return setTimeField(val, field, value)
+		case multipart.FileHeader:
+			// files are bound separately from *multipart.Form; leave the
+			// zero value in place instead of failing to JSON-decode it.
+			return nil
+		}
+		return json.Unmarshal([]byte(val), value.Addr().Interface())
+	case reflect.Map:
+		return json.Unmarshal([]byte(val), value.Addr().Interface())
+	default:
+		return errUnknownType
+	}
+	return nil
+}
+
+func setIntField(val string, bitSize int, field reflect.Value) error {
+	if val == "" {
+		val = "0"
+	}
+	intVal, err := strconv.ParseInt(val, 10, bitSize)
+	if err == nil {
+		field.SetInt(intVal)
+	}
+	return err
+}
+
+func setUintField(val string, bitSize int, field reflect.Value) error {
+	if val == "" {
+		val = "0"
+	}
+	uintVal, err := strconv.ParseUint(val, 10, bitSize)
+	if err == nil {
+		field.SetUint(uintVal)
+	}
+	return err
+}
+
+func setBoolField(val string, field reflect.Value) error {
+	if val == "" {
+		val = "false"
+	}
+	boolVal, err := strconv.ParseBool(val)
+	if err == nil {
+		field.SetBool(boolVal)
+	}
+	return err
+}
+
+func setFloatField(val string, bitSize int, field reflect.Value) error {
+	if val == "" {
+		val = "0"
+	}
+	floatVal, err := strconv.ParseFloat(val, bitSize)
+	if err == nil {
+		field.SetFloat(floatVal)
+	}
+	return err
+}
+
+func setTimeField(val string, structField reflect.StructField, value reflect.Value) error {
+	timeFormat := structField.Tag.Get("time_format")
+	if timeFormat == "" {
+		timeFormat = time.RFC3339
+	}
+
+	switch tf := strings.ToLower(timeFormat); tf {
+	case "unix", "unixnano":
+		tv, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return err
+		}
+
+		d := time.Duration(1)
+		if tf == "unixnano" {
+			d = time.Second
+		}
+
+		value.Set(reflect.ValueOf(time.Unix(tv/int64(d), tv%int64(d))))
+		return nil
+	}
+
+	if val == "" {
+		value.Set(reflect.ValueOf(time.Time{}))
+		return nil
+	}
+
+	l := time.Local
+	if isUTC, _ := strconv.ParseBool(structField.Tag.Get("time_utc")); isUTC {
+		l = time.UTC
+	}
+
+	if locTag := structField.Tag.Get("time_location"); locTag != "" {
+		loc, err := time.LoadLocation(locTag)
+		if err != nil {
+			return err
+		}
+		l = loc
+	}
+
+	t, err := time.ParseInLocation(timeFormat, val, l)
+	if err != nil {
+		return err
+	}
+
+	value.Set(reflect.ValueOf(t))
+	return nil
+}
+
+// splitByDelim splits a single incoming value on delim, e.g. "?ids=1,2,3"
+// with `form:"ids" delim:","`. It leaves already-repeated values (ids=1&ids=2)
+// alone, since there's nothing to split in that case.
+func splitByDelim(vs []string, delim string) []string {
+	if delim == "" || len(vs) != 1 {
+		return vs
+	}
+	return strings.Split(vs[0], delim)
+}
+
+func setArray(vals []string, value reflect.Value, field reflect.StructField) error {
+	for i, s := range vals {
+		err := setWithProperType(s, value.Index(i), field)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setSlice(vals []string, value reflect.Value, field reflect.StructField) error {
+	slice := reflect.MakeSlice(value.Type(), len(vals), len(vals))
+	err := setArray(vals, slice, field)
+	if err != nil {
+		return err
+	}
+	value.Set(slice)
+	return nil
+}
+
+func setTimeDuration(val string, value reflect.Value) error {
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		return err
+	}
+	value.Set(reflect.ValueOf(d))
+	return nil
+}
+
+func head(str, sep string) (head string, tail string) {
+	idx := strings.Index(str, sep)
+	if idx < 0 {
+		return str, ""
+	}
+	return str[:idx], str[idx+len(sep):]
+}
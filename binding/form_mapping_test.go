@@ -58,7 +58,8 @@ func TestMappingBaseTypes(t *testing.T) {
 
 		field := val.Elem().Type().Field(0)
 
-		_, err := mapping(val, emptyField, formSource{field.Name: {tt.form}}, "form")
+		tree := buildFormTree(map[string][]string{field.Name: {tt.form}})
+		_, err := mapping(val, emptyField, formTreeSource{tree: tree, tag: "form"}, "form")
 		assert.NoError(t, err, testName)
 
 		actual := val.Elem().Field(0).Interface()
@@ -72,7 +73,7 @@ func TestMappingDefault(t *testing.T) {
 		Slice []int  `form:",default=9"`
 		Array [1]int `form:",default=9"`
 	}
-	err := mappingByPtr(&s, formSource{}, "form")
+	err := mapFormByTag(&s, map[string][]string{}, "form")
 	assert.NoError(t, err)
 
 	assert.Equal(t, 9, s.Int)
@@ -84,7 +85,7 @@ func TestMappingSkipField(t *testing.T) {
 	var s struct {
 		A int
 	}
-	err := mappingByPtr(&s, formSource{}, "form")
+	err := mapFormByTag(&s, map[string][]string{}, "form")
 	assert.NoError(t, err)
 
 	assert.Equal(t, 0, s.A)
@@ -95,7 +96,7 @@ func TestMappingIgnoreField(t *testing.T) {
 		A int `form:"A"`
 		B int `form:"-"`
 	}
-	err := mappingByPtr(&s, formSource{"A": {"9"}, "B": {"9"}}, "form")
+	err := mapFormByTag(&s, map[string][]string{"A": {"9"}, "B": {"9"}}, "form")
 	assert.NoError(t, err)
 
 	assert.Equal(t, 9, s.A)
@@ -107,7 +108,7 @@ func TestMappingUnexportedField(t *testing.T) {
 		A int `form:"a"`
 		b int `form:"b"`
 	}
-	err := mappingByPtr(&s, formSource{"a": {"9"}, "b": {"9"}}, "form")
+	err := mapFormByTag(&s, map[string][]string{"a": {"9"}, "b": {"9"}}, "form")
 	assert.NoError(t, err)
 
 	assert.Equal(t, 9, s.A)
@@ -118,7 +119,7 @@ func TestMappingPrivateField(t *testing.T) {
 	var s struct {
 		f int `form:"field"`
 	}
-	err := mappingByPtr(&s, formSource{"field": {"6"}}, "form")
+	err := mapFormByTag(&s, map[string][]string{"field": {"6"}}, "form")
 	assert.NoError(t, err)
 	assert.Equal(t, 0, s.f)
 }
@@ -128,7 +129,7 @@ func TestMappingUnknownFieldType(t *testing.T) {
 		U uintptr
 	}
 
-	err := mappingByPtr(&s, formSource{"U": {"unknown"}}, "form")
+	err := mapFormByTag(&s, map[string][]string{"U": {"unknown"}}, "form")
 	assert.Error(t, err)
 	assert.Equal(t, errUnknownType, err)
 }
@@ -211,12 +212,12 @@ func TestMappingTimeDuration(t *testing.T) {
 	}
 
 	// ok
-	err := mappingByPtr(&s, formSource{"D": {"5s"}}, "form")
+	err := mapFormByTag(&s, map[string][]string{"D": {"5s"}}, "form")
 	assert.NoError(t, err)
 	assert.Equal(t, 5*time.Second, s.D)
 
 	// error
-	err = mappingByPtr(&s, formSource{"D": {"wrong"}}, "form")
+	err = mapFormByTag(&s, map[string][]string{"D": {"wrong"}}, "form")
 	assert.Error(t, err)
 }
 
@@ -239,7 +240,7 @@ func TestMappingAny(t *testing.T) {
 
 	var s sT
 	// ok
-	err := mappingByPtr(&s, formSource{"Value": {"1"}, "PValue": {"p1"}, "PPValue": {"pp1"}, "dv2": {"aaa2"}}, "form")
+	err := mapFormByTag(&s, map[string][]string{"Value": {"1"}, "PValue": {"p1"}, "PPValue": {"pp1"}, "dv2": {"aaa2"}}, "form")
 	assert.NoError(t, err)
 	assert.True(t, noNil(&s))
 	assert.Equal(t, "1", s.Value)
@@ -251,7 +252,7 @@ func TestMappingAny(t *testing.T) {
 
 	var s2 sT
 	// ok
-	err = mappingByPtr(&s2, formSource{"Value": {"1", "a2"}, "PValue": {"p1", "2.0"}, "PPValue": {"pp1", "2.00"}, "dv3": {"3", "33"}}, "form")
+	err = mapFormByTag(&s2, map[string][]string{"Value": {"1", "a2"}, "PValue": {"p1", "2.0"}, "PPValue": {"pp1", "2.00"}, "dv3": {"3", "33"}}, "form")
 	assert.NoError(t, err)
 	assert.True(t, noNil(&s2))
 	assert.Equal(t, []string{"1", "a2"}, s2.Value)
@@ -281,8 +282,8 @@ func TestMappingSliceArrayAny(t *testing.T) {
 	}
 
 	// ok
-	err := mappingByPtr(&s,
-		formSource{
+	err := mapFormByTag(&s,
+		map[string][]string{
 			"Values": {"1"}, "PValues": {"p1"}, "PPValues": {"pp1"},
 			"AValues": {"a1", "a2"}, "PAValues": {"pa1", "pa2"}, "PPAValues": {"ppa1", "ppa2"},
 		}, "form")
@@ -297,8 +298,8 @@ func TestMappingSliceArrayAny(t *testing.T) {
 	assert.Equal(t, [2]any{"ppa1", "ppa2"}, *(*(s.PPAValues)))
 
 	// error - not enough vals
-	err = mappingByPtr(&s,
-		formSource{
+	err = mapFormByTag(&s,
+		map[string][]string{
 			"Values": {"1"}, "PValues": {"p1"}, "PPValues": {"pp1"},
 			"AValues": {"a1", "a2"}, "PAValues": {"pa1"}, "PPAValues": {"ppa1", "ppa2"},
 		}, "form")
@@ -311,17 +312,17 @@ func TestMappingSlice(t *testing.T) {
 	}
 
 	// default value
-	err := mappingByPtr(&s, formSource{}, "form")
+	err := mapFormByTag(&s, map[string][]string{}, "form")
 	assert.NoError(t, err)
 	assert.Equal(t, []int{9}, s.Slice)
 
 	// ok
-	err = mappingByPtr(&s, formSource{"slice": {"3", "4"}}, "form")
+	err = mapFormByTag(&s, map[string][]string{"slice": {"3", "4"}}, "form")
 	assert.NoError(t, err)
 	assert.Equal(t, []int{3, 4}, s.Slice)
 
 	// error
-	err = mappingByPtr(&s, formSource{"slice": {"wrong"}}, "form")
+	err = mapFormByTag(&s, map[string][]string{"slice": {"wrong"}}, "form")
 	assert.Error(t, err)
 }
 
@@ -331,23 +332,258 @@ func TestMappingArray(t *testing.T) {
 	}
 
 	// wrong default
-	err := mappingByPtr(&s, formSource{}, "form")
+	err := mapFormByTag(&s, map[string][]string{}, "form")
 	assert.Error(t, err)
 
 	// ok
-	err = mappingByPtr(&s, formSource{"array": {"3", "4"}}, "form")
+	err = mapFormByTag(&s, map[string][]string{"array": {"3", "4"}}, "form")
 	assert.NoError(t, err)
 	assert.Equal(t, [2]int{3, 4}, s.Array)
 
 	// error - not enough vals
-	err = mappingByPtr(&s, formSource{"array": {"3"}}, "form")
+	err = mapFormByTag(&s, map[string][]string{"array": {"3"}}, "form")
 	assert.Error(t, err)
 
 	// error - wrong value
-	err = mappingByPtr(&s, formSource{"array": {"wrong"}}, "form")
+	err = mapFormByTag(&s, map[string][]string{"array": {"wrong"}}, "form")
 	assert.Error(t, err)
 }
 
+func TestMappingSliceWithDelim(t *testing.T) {
+	var s struct {
+		Slice []int `form:"slice,delim=|"`
+	}
+
+	// single value gets split on the delimiter
+	err := mapFormByTag(&s, map[string][]string{"slice": {"3|4|5"}}, "form")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 4, 5}, s.Slice)
+
+	// repeated keys are left untouched
+	err = mapFormByTag(&s, map[string][]string{"slice": {"3", "4"}}, "form")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 4}, s.Slice)
+
+	// error - a token fails to convert
+	err = mapFormByTag(&s, map[string][]string{"slice": {"3|wrong"}}, "form")
+	assert.Error(t, err)
+}
+
+func TestMappingSliceWithDedicatedDelimTag(t *testing.T) {
+	var s struct {
+		Slice []int `form:"slice" delim:"|"`
+	}
+
+	err := mapFormByTag(&s, map[string][]string{"slice": {"3|4|5"}}, "form")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 4, 5}, s.Slice)
+}
+
+func TestMappingSliceWithDefaultAndDelim(t *testing.T) {
+	var s struct {
+		Slice []int `form:"slice,default=3|4,delim=|"`
+	}
+
+	err := mapFormByTag(&s, map[string][]string{}, "form")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 4}, s.Slice)
+}
+
+func TestMappingArrayWithDelim(t *testing.T) {
+	var s struct {
+		Array [2]int `form:"array" delim:","`
+	}
+
+	// ok
+	err := mapFormByTag(&s, map[string][]string{"array": {"3,4"}}, "form")
+	assert.NoError(t, err)
+	assert.Equal(t, [2]int{3, 4}, s.Array)
+
+	// error - length mismatch after splitting
+	err = mapFormByTag(&s, map[string][]string{"array": {"3,4,5"}}, "form")
+	assert.Error(t, err)
+}
+
+func TestMappingSliceWithQuotedDelim(t *testing.T) {
+	var s struct {
+		Slice []string `form:"slice" delim:"\"; \""`
+	}
+
+	err := mapFormByTag(&s, map[string][]string{"slice": {"a; b; c"}}, "form")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, s.Slice)
+}
+
+type bracketNestedItem struct {
+	Key int64 `form:"key"`
+}
+
+type bracketNestedItemsRequest struct {
+	Items []*bracketNestedItem `form:"items"`
+}
+
+type ShadowTags struct {
+	Tags []string `form:"tags,allowshadow,default=inner"`
+}
+
+type midWithShadow struct {
+	Tags []string `form:"tags,allowshadow"`
+	ShadowTags
+}
+
+func TestMappingAllowShadowEmbeddedStruct(t *testing.T) {
+	var s struct {
+		Tags []string `form:"tags,allowshadow,default=outer"`
+		ShadowTags
+	}
+	err := mapFormByTag(&s, map[string][]string{}, "form")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"outer"}, s.Tags)
+	assert.Equal(t, []string{"outer", "inner"}, s.ShadowTags.Tags)
+}
+
+func TestMappingAllowShadowPointerToEmbeddedStruct(t *testing.T) {
+	var s struct {
+		Tags []string `form:"tags,allowshadow,default=outer"`
+		*ShadowTags
+	}
+	err := mapFormByTag(&s, map[string][]string{}, "form")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"outer"}, s.Tags)
+	assert.Equal(t, []string{"outer", "inner"}, s.ShadowTags.Tags)
+}
+
+func TestMappingAllowShadowWithRepeatedFormKey(t *testing.T) {
+	var s struct {
+		Tags []string `form:"tags,allowshadow"`
+		ShadowTags
+	}
+	err := mapFormByTag(&s, map[string][]string{"tags": {"a", "b"}}, "form")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"a", "b"}, s.Tags)
+	assert.Equal(t, []string{"a", "b", "a", "b"}, s.ShadowTags.Tags)
+}
+
+func TestMappingAllowShadowThroughDottedKey(t *testing.T) {
+	var s struct {
+		Mid midWithShadow `form:"mid"`
+	}
+	err := mapForm(&s, map[string][]string{"mid.tags": {"x", "y"}})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"x", "y"}, s.Mid.Tags)
+	assert.Equal(t, []string{"x", "y", "x", "y"}, s.Mid.ShadowTags.Tags)
+}
+
+func TestMappingAllowShadowDottedKeyDoesNotLeakIntoSiblingScope(t *testing.T) {
+	var s struct {
+		Tags []string      `form:"tags,allowshadow,default=TOPLEVEL"`
+		Mid  midWithShadow `form:"mid"`
+	}
+	err := mapForm(&s, map[string][]string{"mid.tags": {"x", "y"}})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"TOPLEVEL"}, s.Tags)
+	assert.Equal(t, []string{"x", "y"}, s.Mid.Tags)
+	assert.Equal(t, []string{"x", "y", "x", "y"}, s.Mid.ShadowTags.Tags)
+}
+
+func TestMappingWithoutAllowShadowOverwrites(t *testing.T) {
+	type base struct {
+		Tags []string `form:"tags,default=inner"`
+	}
+	var s struct {
+		Tags []string `form:"tags,default=outer"`
+		base
+	}
+	err := mapFormByTag(&s, map[string][]string{}, "form")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"outer"}, s.Tags)
+	assert.Equal(t, []string{"inner"}, s.base.Tags)
+}
+
+func TestMappingFormBracketIndexedSlice(t *testing.T) {
+	var s bracketNestedItemsRequest
+	err := mapForm(&s, map[string][]string{
+		"items[0].key": {"1"},
+		"items[1].key": {"2"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, s.Items, 2)
+	assert.EqualValues(t, 1, s.Items[0].Key)
+	assert.EqualValues(t, 2, s.Items[1].Key)
+}
+
+func TestMappingFormDottedNestedStruct(t *testing.T) {
+	type userInfo struct {
+		Name string `form:"name"`
+	}
+	var s struct {
+		User userInfo `form:"user"`
+	}
+	err := mapForm(&s, map[string][]string{"user.name": {"jo"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "jo", s.User.Name)
+}
+
+func TestMappingFormBracketMap(t *testing.T) {
+	var s struct {
+		M map[string]string `form:"m"`
+	}
+	err := mapForm(&s, map[string][]string{"m[foo]": {"bar"}})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"foo": "bar"}, s.M)
+}
+
+func TestMappingFormBracketMapWithNumericKeys(t *testing.T) {
+	var s struct {
+		M map[string]string `form:"m"`
+	}
+	err := mapForm(&s, map[string][]string{"m[123]": {"bar"}, "m[abc]": {"baz"}})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"123": "bar", "abc": "baz"}, s.M)
+}
+
+func TestMappingFormBracketStillAllowsUnbracketedKeys(t *testing.T) {
+	var s struct {
+		F int `form:"field"`
+	}
+	err := mapForm(&s, map[string][]string{"field": {"6"}})
+	assert.NoError(t, err)
+	assert.Equal(t, 6, s.F)
+}
+
+func TestMappingFormBracketIndexCap(t *testing.T) {
+	var s struct {
+		Items []int `form:"items"`
+	}
+	err := mapForm(&s, map[string][]string{"items[999999999]": {"1"}})
+	assert.NoError(t, err)
+	assert.Empty(t, s.Items)
+}
+
+func TestMappingFormBracketSparseIndexCap(t *testing.T) {
+	var s struct {
+		Items []int `form:"items"`
+	}
+	err := mapForm(&s, map[string][]string{"items[99999]": {"1"}})
+	assert.NoError(t, err)
+	assert.Empty(t, s.Items)
+}
+
+func TestMappingFormBracketMapNonStringKey(t *testing.T) {
+	var s struct {
+		M map[int]string `form:"m"`
+	}
+	err := mapForm(&s, map[string][]string{"m[5]": {"bar"}})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]string{5: "bar"}, s.M)
+}
+
 func TestMappingStructField(t *testing.T) {
 	var s struct {
 		J struct {
@@ -355,7 +591,7 @@ func TestMappingStructField(t *testing.T) {
 		}
 	}
 
-	err := mappingByPtr(&s, formSource{"J": {`{"I": 9}`}}, "form")
+	err := mapFormByTag(&s, map[string][]string{"J": {`{"I": 9}`}}, "form")
 	assert.NoError(t, err)
 	assert.Equal(t, 9, s.J.I)
 }
@@ -373,20 +609,20 @@ func TestMappingPtrField(t *testing.T) {
 
 	// With 0 items.
 	var req0 ptrRequest
-	err = mappingByPtr(&req0, formSource{}, "form")
+	err = mapFormByTag(&req0, map[string][]string{}, "form")
 	assert.NoError(t, err)
 	assert.Empty(t, req0.Items)
 
 	// With 1 item.
 	var req1 ptrRequest
-	err = mappingByPtr(&req1, formSource{"items": {`{"key": 1}`}}, "form")
+	err = mapFormByTag(&req1, map[string][]string{"items": {`{"key": 1}`}}, "form")
 	assert.NoError(t, err)
 	assert.Len(t, req1.Items, 1)
 	assert.EqualValues(t, 1, req1.Items[0].Key)
 
 	// With 2 items.
 	var req2 ptrRequest
-	err = mappingByPtr(&req2, formSource{"items": {`{"key": 1}`, `{"key": 2}`}}, "form")
+	err = mapFormByTag(&req2, map[string][]string{"items": {`{"key": 1}`, `{"key": 2}`}}, "form")
 	assert.NoError(t, err)
 	assert.Len(t, req2.Items, 2)
 	assert.EqualValues(t, 1, req2.Items[0].Key)
@@ -398,7 +634,7 @@ func TestMappingMapField(t *testing.T) {
 		M map[string]int
 	}
 
-	err := mappingByPtr(&s, formSource{"M": {`{"one": 1}`}}, "form")
+	err := mapFormByTag(&s, map[string][]string{"M": {`{"one": 1}`}}, "form")
 	assert.NoError(t, err)
 	assert.Equal(t, map[string]int{"one": 1}, s.M)
 }
@@ -409,7 +645,7 @@ func TestMappingIgnoredCircularRef(t *testing.T) {
 	}
 	var s S
 
-	err := mappingByPtr(&s, formSource{}, "form")
+	err := mapFormByTag(&s, map[string][]string{}, "form")
 	assert.NoError(t, err)
 }
 
@@ -428,7 +664,7 @@ func TestMappingCustomUnmarshalParamHexWithFormTag(t *testing.T) {
 	var s struct {
 		Foo customUnmarshalParamHex `form:"foo"`
 	}
-	err := mappingByPtr(&s, formSource{"foo": {`f5`}}, "form")
+	err := mapFormByTag(&s, map[string][]string{"foo": {`f5`}}, "form")
 	assert.NoError(t, err)
 
 	assert.EqualValues(t, 245, s.Foo)
@@ -438,7 +674,7 @@ func TestMappingCustomUnmarshalParamHexWithURITag(t *testing.T) {
 	var s struct {
 		Foo customUnmarshalParamHex `uri:"foo"`
 	}
-	err := mappingByPtr(&s, formSource{"foo": {`f5`}}, "uri")
+	err := mapFormByTag(&s, map[string][]string{"foo": {`f5`}}, "uri")
 	assert.NoError(t, err)
 
 	assert.EqualValues(t, 245, s.Foo)
@@ -465,7 +701,7 @@ func TestMappingCustomStructTypeWithFormTag(t *testing.T) {
 	var s struct {
 		FileData customUnmarshalParamType `form:"data"`
 	}
-	err := mappingByPtr(&s, formSource{"data": {`file:/foo:happiness`}}, "form")
+	err := mapFormByTag(&s, map[string][]string{"data": {`file:/foo:happiness`}}, "form")
 	assert.NoError(t, err)
 
 	assert.EqualValues(t, "file", s.FileData.Protocol)
@@ -477,7 +713,7 @@ func TestMappingCustomStructTypeWithURITag(t *testing.T) {
 	var s struct {
 		FileData customUnmarshalParamType `uri:"data"`
 	}
-	err := mappingByPtr(&s, formSource{"data": {`file:/foo:happiness`}}, "uri")
+	err := mapFormByTag(&s, map[string][]string{"data": {`file:/foo:happiness`}}, "uri")
 	assert.NoError(t, err)
 
 	assert.EqualValues(t, "file", s.FileData.Protocol)
@@ -489,7 +725,7 @@ func TestMappingCustomPointerStructTypeWithFormTag(t *testing.T) {
 	var s struct {
 		FileData *customUnmarshalParamType `form:"data"`
 	}
-	err := mappingByPtr(&s, formSource{"data": {`file:/foo:happiness`}}, "form")
+	err := mapFormByTag(&s, map[string][]string{"data": {`file:/foo:happiness`}}, "form")
 	assert.NoError(t, err)
 
 	assert.EqualValues(t, "file", s.FileData.Protocol)
@@ -501,7 +737,7 @@ func TestMappingCustomPointerStructTypeWithURITag(t *testing.T) {
 	var s struct {
 		FileData *customUnmarshalParamType `uri:"data"`
 	}
-	err := mappingByPtr(&s, formSource{"data": {`file:/foo:happiness`}}, "uri")
+	err := mapFormByTag(&s, map[string][]string{"data": {`file:/foo:happiness`}}, "uri")
 	assert.NoError(t, err)
 
 	assert.EqualValues(t, "file", s.FileData.Protocol)
@@ -526,7 +762,7 @@ func TestMappingCustomSliceUri(t *testing.T) {
 	var s struct {
 		FileData customPath `uri:"path"`
 	}
-	err := mappingByPtr(&s, formSource{"path": {`bar/foo`}}, "uri")
+	err := mapFormByTag(&s, map[string][]string{"path": {`bar/foo`}}, "uri")
 	assert.NoError(t, err)
 
 	assert.EqualValues(t, "bar", s.FileData[0])
@@ -537,7 +773,7 @@ func TestMappingCustomSliceForm(t *testing.T) {
 	var s struct {
 		FileData customPath `form:"path"`
 	}
-	err := mappingByPtr(&s, formSource{"path": {`bar/foo`}}, "form")
+	err := mapFormByTag(&s, map[string][]string{"path": {`bar/foo`}}, "form")
 	assert.NoError(t, err)
 
 	assert.EqualValues(t, "bar", s.FileData[0])
@@ -576,7 +812,7 @@ func TestMappingCustomArrayUri(t *testing.T) {
 		FileData objectID `uri:"id"`
 	}
 	val := `664a062ac74a8ad104e0e80f`
-	err := mappingByPtr(&s, formSource{"id": {val}}, "uri")
+	err := mapFormByTag(&s, map[string][]string{"id": {val}}, "uri")
 	assert.NoError(t, err)
 
 	expected, _ := convertTo(val)
@@ -588,9 +824,127 @@ func TestMappingCustomArrayForm(t *testing.T) {
 		FileData objectID `form:"id"`
 	}
 	val := `664a062ac74a8ad104e0e80f`
-	err := mappingByPtr(&s, formSource{"id": {val}}, "form")
+	err := mapFormByTag(&s, map[string][]string{"id": {val}}, "form")
 	assert.NoError(t, err)
 
 	expected, _ := convertTo(val)
 	assert.EqualValues(t, expected, s.FileData)
 }
+
+type customTags []string
+
+func (p *customTags) UnmarshalForm(values []string) error {
+	*p = values
+	return nil
+}
+
+func TestMappingCustomUnmarshalFormSlice(t *testing.T) {
+	var s struct {
+		Tags customTags `form:"tags"`
+	}
+	err := mapFormByTag(&s, map[string][]string{"tags": {"a", "b", "c"}}, "form")
+	assert.NoError(t, err)
+
+	assert.Equal(t, customTags{"a", "b", "c"}, s.Tags)
+}
+
+type customMultiValue struct {
+	Values []string
+}
+
+func (f *customMultiValue) UnmarshalForm(values []string) error {
+	f.Values = values
+	return nil
+}
+
+func TestMappingCustomUnmarshalFormStructField(t *testing.T) {
+	var s struct {
+		Data customMultiValue `form:"data"`
+	}
+	err := mapFormByTag(&s, map[string][]string{"data": {"x", "y"}}, "form")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"x", "y"}, s.Data.Values)
+}
+
+func TestMappingCustomUnmarshalFormPointerField(t *testing.T) {
+	var s struct {
+		Data *customMultiValue `form:"data"`
+	}
+	err := mapFormByTag(&s, map[string][]string{"data": {"x", "y", "z"}}, "form")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"x", "y", "z"}, s.Data.Values)
+}
+
+// customSingleValue only implements the single-value Unmarshaler, so it
+// must keep working unchanged when FormUnmarshaler is not implemented.
+type customSingleValue string
+
+func (p *customSingleValue) UnmarshalParam(param string) error {
+	*p = customSingleValue(param)
+	return nil
+}
+
+func TestMappingCustomUnmarshalParamStillPreferredFallback(t *testing.T) {
+	var s struct {
+		Foo customSingleValue `form:"foo"`
+	}
+	err := mapFormByTag(&s, map[string][]string{"foo": {"first", "second"}}, "form")
+	assert.NoError(t, err)
+
+	assert.EqualValues(t, "first", s.Foo)
+}
+
+// thirdPartyPoint stands in for a third-party type (uuid.UUID,
+// decimal.Decimal, ...) that can't implement Unmarshaler/FormUnmarshaler
+// itself, so binding it requires a registered converter instead.
+type thirdPartyPoint struct {
+	X, Y int
+}
+
+func TestMappingRegisterCustomTypeFuncThirdPartyStruct(t *testing.T) {
+	RegisterCustomTypeFunc(func(vals []string) (any, error) {
+		parts := strings.Split(vals[0], ",")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid point %q", vals[0])
+		}
+		x, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return nil, err
+		}
+		y, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, err
+		}
+		return thirdPartyPoint{X: x, Y: y}, nil
+	}, thirdPartyPoint{})
+
+	var s struct {
+		Origin thirdPartyPoint `form:"origin"`
+	}
+	err := mapForm(&s, map[string][]string{"origin": {"3,4"}})
+	assert.NoError(t, err)
+	assert.Equal(t, thirdPartyPoint{X: 3, Y: 4}, s.Origin)
+}
+
+func TestMappingCustomTypeFuncOverridesTimeFormat(t *testing.T) {
+	opts := FormOptions{
+		CustomTypes: map[reflect.Type]CustomTypeFunc{
+			reflect.TypeOf(time.Time{}): func(vals []string) (any, error) {
+				sec, err := strconv.ParseInt(vals[0], 10, 64)
+				if err != nil {
+					return nil, err
+				}
+				return time.Unix(sec, 0).UTC(), nil
+			},
+		},
+	}
+
+	var s struct {
+		CreatedAt time.Time `form:"created_at" time_format:"2006-01-02"`
+	}
+	err := MapFormWithOptions(&s, map[string][]string{"created_at": {"1700000000"}}, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Unix(1700000000, 0).UTC(), s.CreatedAt)
+}